@@ -0,0 +1,187 @@
+// Package browsingdata はブラウザを起動せずにChromeプロファイルの
+// Cookie・保存済みログイン情報を直接読み取り、復号して返す
+package browsingdata
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	webview "github.com/Azunyan1111/rod-wrap"
+)
+
+// saltySalt はChromeが鍵導出に使う固定ソルト
+const saltySalt = "saltysalt"
+
+// Cookie は復号済みのChrome Cookieを表す
+type Cookie struct {
+	Host       string
+	Name       string
+	Value      string
+	Path       string
+	ExpiresUTC int64
+	IsSecure   bool
+	IsHTTPOnly bool
+	SameSite   int
+}
+
+// Login は復号済みのChrome保存済みログイン情報を表す
+type Login struct {
+	Origin   string
+	Username string
+	Password string
+}
+
+// HistoryEntry はChromeの閲覧履歴エントリを表す
+type HistoryEntry struct {
+	URL           string
+	Title         string
+	VisitCount    int
+	LastVisitTime int64 // Chrome時刻形式（1601-01-01からのマイクロ秒）
+}
+
+// ExportCookies は指定したプロファイルのCookies DBを復号して取得する
+// プロファイルを直接読み取るため、既存のChromeが起動中でも利用できる
+// （WithCopiedProfileと異なりブラウザの起動を必要としない）
+func ExportCookies(profile webview.ChromeProfile) ([]Cookie, error) {
+	key, err := masterKey(profile)
+	if err != nil {
+		return nil, fmt.Errorf("browsingdata: resolve master key: %w", err)
+	}
+
+	dbPath, cleanup, err := copyLockedDB(filepath.Join(profile.Path, "Cookies"))
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("browsingdata: open cookies db: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT host_key, name, encrypted_value, path, expires_utc, is_secure, is_httponly, samesite FROM cookies`)
+	if err != nil {
+		return nil, fmt.Errorf("browsingdata: query cookies: %w", err)
+	}
+	defer rows.Close()
+
+	var cookies []Cookie
+	for rows.Next() {
+		var c Cookie
+		var encrypted []byte
+		if err := rows.Scan(&c.Host, &c.Name, &encrypted, &c.Path, &c.ExpiresUTC, &c.IsSecure, &c.IsHTTPOnly, &c.SameSite); err != nil {
+			return nil, fmt.Errorf("browsingdata: scan cookie row: %w", err)
+		}
+		value, err := decryptValue(encrypted, key)
+		if err != nil {
+			slog.Warn("ExportCookies: failed to decrypt cookie", "name", c.Name, "error", err)
+			continue
+		}
+		c.Value = value
+		cookies = append(cookies, c)
+	}
+	return cookies, rows.Err()
+}
+
+// ExportPasswords は指定したプロファイルのLogin Data DBを復号して取得する
+func ExportPasswords(profile webview.ChromeProfile) ([]Login, error) {
+	key, err := masterKey(profile)
+	if err != nil {
+		return nil, fmt.Errorf("browsingdata: resolve master key: %w", err)
+	}
+
+	dbPath, cleanup, err := copyLockedDB(filepath.Join(profile.Path, "Login Data"))
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("browsingdata: open login data db: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT origin_url, username_value, password_value FROM logins`)
+	if err != nil {
+		return nil, fmt.Errorf("browsingdata: query logins: %w", err)
+	}
+	defer rows.Close()
+
+	var logins []Login
+	for rows.Next() {
+		var l Login
+		var encrypted []byte
+		if err := rows.Scan(&l.Origin, &l.Username, &encrypted); err != nil {
+			return nil, fmt.Errorf("browsingdata: scan login row: %w", err)
+		}
+		value, err := decryptValue(encrypted, key)
+		if err != nil {
+			slog.Warn("ExportPasswords: failed to decrypt password", "origin", l.Origin, "error", err)
+			continue
+		}
+		l.Password = value
+		logins = append(logins, l)
+	}
+	return logins, rows.Err()
+}
+
+// ExportHistory は指定したプロファイルのHistory DBからurlsテーブルを読み取る
+// History DBはCookies/Login Dataと異なり暗号化されていないため復号は不要
+func ExportHistory(profile webview.ChromeProfile) ([]HistoryEntry, error) {
+	dbPath, cleanup, err := copyLockedDB(filepath.Join(profile.Path, "History"))
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("browsingdata: open history db: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT url, title, visit_count, last_visit_time FROM urls`)
+	if err != nil {
+		return nil, fmt.Errorf("browsingdata: query history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []HistoryEntry
+	for rows.Next() {
+		var h HistoryEntry
+		if err := rows.Scan(&h.URL, &h.Title, &h.VisitCount, &h.LastVisitTime); err != nil {
+			return nil, fmt.Errorf("browsingdata: scan history row: %w", err)
+		}
+		history = append(history, h)
+	}
+	return history, rows.Err()
+}
+
+// copyLockedDB はChrome実行中でもロックされたSQLite DBを読めるよう一時ファイルに
+// コピーする。呼び出し側はcleanupで一時ファイルを削除すること
+func copyLockedDB(path string) (dbPath string, cleanup func(), err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("browsingdata: read %s: %w", path, err)
+	}
+
+	tmp, err := os.CreateTemp("", "rod-wrap-browsingdata-*.db")
+	if err != nil {
+		return "", nil, fmt.Errorf("browsingdata: create temp db: %w", err)
+	}
+	defer tmp.Close()
+
+	if _, err := tmp.Write(data); err != nil {
+		os.Remove(tmp.Name())
+		return "", nil, fmt.Errorf("browsingdata: write temp db: %w", err)
+	}
+
+	return tmp.Name(), func() { os.Remove(tmp.Name()) }, nil
+}
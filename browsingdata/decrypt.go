@@ -0,0 +1,102 @@
+package browsingdata
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+)
+
+// cbcIV はmacOS/LinuxがAES-128-CBCで使う固定IV（スペース16バイト）
+var cbcIV = bytes.Repeat([]byte{0x20}, aes.BlockSize)
+
+// decryptValue はChromeのv10/v11プレフィックス付き暗号化値を復号する
+// Windows（32バイト鍵）ではDPAPIで得た鍵でAES-256-GCMを使う
+// （レイアウトは prefix(3) || nonce(12) || ciphertext || tag(16)）。
+// macOS/Linux（16バイト鍵）ではPBKDF2で得た鍵でAES-128-CBC、固定IV（スペース16バイト）、
+// PKCS7パディングを使う（レイアウトは prefix(3) || ciphertext、nonceは無い）。
+func decryptValue(encrypted, key []byte) (string, error) {
+	if len(encrypted) < 3 {
+		return "", fmt.Errorf("browsingdata: encrypted value too short")
+	}
+
+	prefix := string(encrypted[:3])
+	if prefix != "v10" && prefix != "v11" {
+		return "", fmt.Errorf("browsingdata: unsupported encryption prefix %q", prefix)
+	}
+
+	if len(key) == 32 {
+		return decryptGCM(encrypted, key)
+	}
+	return decryptCBC(encrypted, key)
+}
+
+// decryptGCM はWindowsのAES-256-GCM形式を復号する
+func decryptGCM(encrypted, key []byte) (string, error) {
+	if len(encrypted) < 15 {
+		return "", fmt.Errorf("browsingdata: encrypted value too short for gcm")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", fmt.Errorf("browsingdata: new cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", fmt.Errorf("browsingdata: new gcm: %w", err)
+	}
+
+	nonce := encrypted[3:15]
+	ciphertext := encrypted[15:] // 末尾16バイトはGCMタグ。gcm.Openがまとめて検証する
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("browsingdata: gcm open: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// decryptCBC はmacOS/LinuxのAES-128-CBC形式（固定IV、PKCS7パディング）を復号する
+func decryptCBC(encrypted, key []byte) (string, error) {
+	ciphertext := encrypted[3:]
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return "", fmt.Errorf("browsingdata: ciphertext is not a multiple of the block size")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", fmt.Errorf("browsingdata: new cipher: %w", err)
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, cbcIV).CryptBlocks(plaintext, ciphertext)
+
+	plaintext, err = pkcs7Unpad(plaintext)
+	if err != nil {
+		return "", fmt.Errorf("browsingdata: cbc unpad: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// pkcs7Unpad はPKCS7パディングを取り除く
+func pkcs7Unpad(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty data")
+	}
+
+	padLen := int(data[len(data)-1])
+	if padLen == 0 || padLen > aes.BlockSize || padLen > len(data) {
+		return nil, fmt.Errorf("invalid padding length %d", padLen)
+	}
+
+	for _, b := range data[len(data)-padLen:] {
+		if int(b) != padLen {
+			return nil, fmt.Errorf("invalid padding bytes")
+		}
+	}
+
+	return data[:len(data)-padLen], nil
+}
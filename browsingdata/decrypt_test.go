@@ -0,0 +1,79 @@
+package browsingdata
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"testing"
+)
+
+func TestDecryptValue_GCM(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("new cipher: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatalf("new gcm: %v", err)
+	}
+
+	nonce := make([]byte, 12)
+	if _, err := rand.Read(nonce); err != nil {
+		t.Fatalf("failed to generate nonce: %v", err)
+	}
+
+	want := "hello-windows-cookie"
+	sealed := gcm.Seal(nil, nonce, []byte(want), nil)
+
+	encrypted := append([]byte("v10"), nonce...)
+	encrypted = append(encrypted, sealed...)
+
+	got, err := decryptValue(encrypted, key)
+	if err != nil {
+		t.Fatalf("decryptValue (gcm) failed: %v", err)
+	}
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestDecryptValue_CBC(t *testing.T) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	want := "hello-mac-linux-cookie"
+	padded := pkcs7Pad([]byte(want), aes.BlockSize)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("new cipher: %v", err)
+	}
+
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, cbcIV).CryptBlocks(ciphertext, padded)
+
+	encrypted := append([]byte("v10"), ciphertext...)
+
+	got, err := decryptValue(encrypted, key)
+	if err != nil {
+		t.Fatalf("decryptValue (cbc) failed: %v", err)
+	}
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// pkcs7Pad はテスト用にPKCS7パディングを付与するヘルパー
+func pkcs7Pad(data []byte, blockSize int) []byte {
+	padLen := blockSize - len(data)%blockSize
+	padding := bytes.Repeat([]byte{byte(padLen)}, padLen)
+	return append(data, padding...)
+}
@@ -0,0 +1,26 @@
+//go:build darwin
+
+package browsingdata
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	webview "github.com/Azunyan1111/rod-wrap"
+)
+
+// masterKey はmacOS Keychainからsafe storageパスワードを取得し、
+// PBKDF2-SHA1で復号鍵を導出する
+func masterKey(profile webview.ChromeProfile) ([]byte, error) {
+	out, err := exec.Command("security", "find-generic-password", "-wa", "Chrome").Output()
+	if err != nil {
+		return nil, fmt.Errorf("browsingdata: read keychain: %w", err)
+	}
+
+	password := strings.TrimSpace(string(out))
+	return pbkdf2.Key([]byte(password), []byte(saltySalt), 1003, 16, sha1.New), nil
+}
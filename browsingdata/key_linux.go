@@ -0,0 +1,33 @@
+//go:build linux
+
+package browsingdata
+
+import (
+	"crypto/sha1"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	webview "github.com/Azunyan1111/rod-wrap"
+)
+
+// linuxFallbackPassword はlibsecret/gnome-keyringが利用できない場合に
+// Chromeが鍵導出に使うハードコードされたパスワード
+const linuxFallbackPassword = "peanuts"
+
+// masterKey はlibsecret(gnome-keyring)からsafe storageパスワードを取得し、
+// 取得できない場合はハードコードされたパスワードにフォールバックして
+// PBKDF2-SHA1で復号鍵を導出する
+func masterKey(profile webview.ChromeProfile) ([]byte, error) {
+	password := linuxFallbackPassword
+	iterations := 1
+
+	if out, err := exec.Command("secret-tool", "lookup", "application", "chrome").Output(); err == nil {
+		if s := strings.TrimSpace(string(out)); s != "" {
+			password = s
+		}
+	}
+
+	return pbkdf2.Key([]byte(password), []byte(saltySalt), iterations, 16, sha1.New), nil
+}
@@ -0,0 +1,87 @@
+//go:build windows
+
+package browsingdata
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+
+	webview "github.com/Azunyan1111/rod-wrap"
+)
+
+var (
+	modcrypt32             = syscall.NewLazyDLL("crypt32.dll")
+	modkernel32            = syscall.NewLazyDLL("kernel32.dll")
+	procCryptUnprotectData = modcrypt32.NewProc("CryptUnprotectData")
+	procLocalFree          = modkernel32.NewProc("LocalFree")
+)
+
+type dataBlob struct {
+	cbData uint32
+	pbData *byte
+}
+
+func newBlob(data []byte) *dataBlob {
+	if len(data) == 0 {
+		return &dataBlob{}
+	}
+	return &dataBlob{cbData: uint32(len(data)), pbData: &data[0]}
+}
+
+// dpapiUnprotect はCryptUnprotectDataでDPAPI保護されたデータを復号する
+func dpapiUnprotect(data []byte) ([]byte, error) {
+	var out dataBlob
+	in := newBlob(data)
+
+	r, _, err := procCryptUnprotectData.Call(
+		uintptr(unsafe.Pointer(in)),
+		0, 0, 0, 0, 0,
+		uintptr(unsafe.Pointer(&out)),
+	)
+	if r == 0 {
+		return nil, fmt.Errorf("browsingdata: CryptUnprotectData: %w", err)
+	}
+	defer procLocalFree.Call(uintptr(unsafe.Pointer(out.pbData)))
+
+	result := make([]byte, out.cbData)
+	copy(result, unsafe.Slice(out.pbData, out.cbData))
+	return result, nil
+}
+
+// masterKey はLocal Stateのos_crypt.encrypted_keyを取得し、
+// 先頭5バイトの"DPAPI"プレフィックスを除去した上でDPAPIで復号する
+func masterKey(profile webview.ChromeProfile) ([]byte, error) {
+	// profile.Path は "...\User Data\<Profile Dir>" なので一つ上がLocal State
+	localStatePath := filepath.Join(filepath.Dir(profile.Path), "Local State")
+
+	data, err := os.ReadFile(localStatePath)
+	if err != nil {
+		return nil, fmt.Errorf("browsingdata: read Local State: %w", err)
+	}
+
+	var localState struct {
+		OSCrypt struct {
+			EncryptedKey string `json:"encrypted_key"`
+		} `json:"os_crypt"`
+	}
+	if err := json.Unmarshal(data, &localState); err != nil {
+		return nil, fmt.Errorf("browsingdata: parse Local State: %w", err)
+	}
+
+	encryptedKey, err := base64.StdEncoding.DecodeString(localState.OSCrypt.EncryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("browsingdata: decode encrypted_key: %w", err)
+	}
+
+	const dpapiPrefix = "DPAPI"
+	if len(encryptedKey) < len(dpapiPrefix) || string(encryptedKey[:len(dpapiPrefix)]) != dpapiPrefix {
+		return nil, fmt.Errorf("browsingdata: encrypted_key missing DPAPI prefix")
+	}
+
+	return dpapiUnprotect(encryptedKey[len(dpapiPrefix):])
+}
@@ -1,11 +1,10 @@
-package rod_wrap
+package webview
 
 import (
 	"context"
 	"log/slog"
 	"os"
 	"sync"
-	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
@@ -13,16 +12,35 @@ import (
 	"github.com/go-rod/stealth"
 )
 
+// rodWrapBindingName はMutationObserver/inputイベントの通知をGo側に渡すために
+// proto.RuntimeAddBindingで登録するグローバル関数名
+const rodWrapBindingName = "__rodWrapValueChanged"
+
 type chromeWebView struct {
-	browser   *rod.Browser
-	page      *rod.Page
-	elements  map[string]string
-	listeners map[string]bool
-	mu        sync.RWMutex
-	ctx       context.Context
-	cancel    context.CancelFunc
-	stopChan  chan struct{}
-	tmpDir    string // 一時ディレクトリ（Destroy時に削除）
+	browser  *rod.Browser
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopChan chan struct{}
+	tmpDir   string // 一時ディレクトリ（Destroy時に削除）
+
+	mu             sync.RWMutex
+	sessions       map[proto.TargetTargetID]*session
+	currentSession *session
+	newTabHandler  func(Session)
+
+	dialogHandler     func(DialogEvent) DialogResponse
+	autoAcceptDialogs bool
+
+	downloadDir      string
+	downloadOnce     sync.Once
+	pendingDownloads map[string]pendingDownload
+	downloads        chan DownloadEvent
+}
+
+// pendingDownload はダウンロード開始から完了までの間、GUIDごとに保持する情報
+type pendingDownload struct {
+	url      string
+	filename string
 }
 
 // NewChromeWebView は新しいChromeWebViewを作成する
@@ -75,107 +93,239 @@ func NewChromeWebView(opts ...ChromeOption) WebView {
 	// ウィンドウサイズを設定
 	page.MustSetWindow(0, 0, 1280, 720)
 
-	return &chromeWebView{
-		browser:   browser,
-		page:      page,
-		elements:  make(map[string]string),
-		listeners: make(map[string]bool),
-		ctx:       ctx,
-		cancel:    cancel,
-		stopChan:  make(chan struct{}),
-		tmpDir:    options.tmpDir,
+	c := &chromeWebView{
+		browser:          browser,
+		ctx:              ctx,
+		cancel:           cancel,
+		stopChan:         make(chan struct{}),
+		tmpDir:           options.tmpDir,
+		sessions:         make(map[proto.TargetTargetID]*session),
+		pendingDownloads: make(map[string]pendingDownload),
+		downloads:        make(chan DownloadEvent, 16),
 	}
+
+	c.registerSession(page, true)
+	c.watchTargets()
+
+	return c
 }
 
-func (c *chromeWebView) Navigate(url string) {
-	slog.Info("Navigate", "url", url)
+// registerSession は新しいページをsessionとして登録する
+// makeCurrentがtrue、またはまだcurrentSessionが無い場合は操作対象に設定する
+func (c *chromeWebView) registerSession(page *rod.Page, makeCurrent bool) *session {
+	sess := newSession(c, page)
 
-	// Navigate前に既存の変数をクリア
 	c.mu.Lock()
-	c.elements = make(map[string]string)
+	c.sessions[sess.id] = sess
+	if makeCurrent || c.currentSession == nil {
+		c.currentSession = sess
+	}
 	c.mu.Unlock()
 
-	// 実際のURLに移動
-	err := c.page.Navigate(url)
-	if err != nil {
-		slog.Error("Navigate failed", "error", err)
-		return
-	}
+	sess.ensureDialogBinding()
+	return sess
+}
+
+// watchTargets はwindow.open・target=_blank・Ctrl+Clickなどで開かれた新しい
+// タブをTargetCreatedイベントから検知し、自動的にsessionとして追跡する
+func (c *chromeWebView) watchTargets() {
+	wait := c.browser.EachEvent(
+		func(e *proto.TargetTargetCreated) {
+			if e.TargetInfo.Type != proto.TargetTargetInfoTypePage {
+				return
+			}
+
+			c.mu.RLock()
+			_, exists := c.sessions[e.TargetInfo.TargetID]
+			c.mu.RUnlock()
+			if exists {
+				return
+			}
+
+			page, err := c.browser.PageFromTarget(e.TargetInfo.TargetID)
+			if err != nil {
+				slog.Warn("watchTargets: failed to attach to new target", "targetID", e.TargetInfo.TargetID, "error", err)
+				return
+			}
+
+			sess := c.registerSession(page, false)
+
+			c.mu.RLock()
+			handler := c.newTabHandler
+			c.mu.RUnlock()
+			if handler != nil {
+				handler(sess)
+			}
+		},
+		func(e *proto.TargetTargetDestroyed) {
+			c.mu.Lock()
+			sess, ok := c.sessions[e.TargetID]
+			if ok {
+				delete(c.sessions, e.TargetID)
+				if c.currentSession == sess {
+					c.currentSession = nil
+					for _, remaining := range c.sessions {
+						c.currentSession = remaining
+						break
+					}
+				}
+			}
+			c.mu.Unlock()
+		},
+	)
+	go wait()
+}
 
-	// ページの読み込みを待機
-	c.page.MustWaitLoad()
+// current は操作対象となっているsessionを返す
+func (c *chromeWebView) current() *session {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.currentSession
+}
 
-	// リスナーが設定されている場合は再設定
+// dialogSettings はダイアログハンドラの現在の設定を返す（sessionから参照される）
+func (c *chromeWebView) dialogSettings() (func(DialogEvent) DialogResponse, bool) {
 	c.mu.RLock()
-	for elementID := range c.listeners {
-		c.setupListener(elementID)
-	}
-	c.mu.RUnlock()
+	defer c.mu.RUnlock()
+	return c.dialogHandler, c.autoAcceptDialogs
 }
 
-func (c *chromeWebView) GetCurrentURL() string {
-	info, err := c.page.Info()
+// NewTab は新しいタブを開き、そのSessionを返す
+func (c *chromeWebView) NewTab(url string) Session {
+	page, err := c.browser.Page(proto.TargetCreateTarget{URL: "about:blank"})
 	if err != nil {
-		slog.Error("GetCurrentURL failed", "error", err)
-		return ""
+		slog.Error("NewTab: failed to create page", "error", err)
+		return nil
 	}
-	return info.URL
+
+	sess := c.registerSession(page, true)
+	if url != "" {
+		sess.Navigate(url)
+	}
+	return sess
 }
 
-func (c *chromeWebView) GetValue(elementID string) string {
+// Tabs は現在開いている全タブのSessionを返す
+func (c *chromeWebView) Tabs() []Session {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.elements[elementID]
+
+	tabs := make([]Session, 0, len(c.sessions))
+	for _, sess := range c.sessions {
+		tabs = append(tabs, sess)
+	}
+	return tabs
 }
 
-func (c *chromeWebView) SetValue(elementID, value string) {
+// Switch は以降の操作対象となるタブを指定したSessionに切り替える
+func (c *chromeWebView) Switch(s Session) {
+	sess, ok := s.(*session)
+	if !ok {
+		slog.Error("Switch: unknown session implementation")
+		return
+	}
+
 	c.mu.Lock()
-	c.elements[elementID] = value
+	defer c.mu.Unlock()
+	if _, tracked := c.sessions[sess.id]; !tracked {
+		slog.Warn("Switch: session is not tracked by this WebView", "session", sess.id)
+		return
+	}
+	c.currentSession = sess
+}
+
+// OnNewTab はwindow.openなどで新しいタブが開かれた際に呼び出されるコールバックを登録する
+func (c *chromeWebView) OnNewTab(cb func(Session)) {
+	c.mu.Lock()
+	c.newTabHandler = cb
 	c.mu.Unlock()
+}
 
-	// 要素を取得して値を設定
-	el, err := c.page.Element("#" + elementID)
-	if err != nil {
-		slog.Error("SetValue: element not found", "elementID", elementID, "error", err)
+// noCurrentSession はcurrent()がnilを返した際に共通してログ出力する
+// （全タブが閉じられた直後などに到達しうる）
+func (c *chromeWebView) noCurrentSession(op string) {
+	slog.Error(op + ": no current session (all tabs closed?)")
+}
+
+func (c *chromeWebView) Navigate(url string) {
+	sess := c.current()
+	if sess == nil {
+		c.noCurrentSession("Navigate")
 		return
 	}
+	sess.Navigate(url)
+}
 
-	err = el.Input(value)
-	if err != nil {
-		slog.Error("SetValue: input failed", "elementID", elementID, "error", err)
+func (c *chromeWebView) GetCurrentURL() string {
+	sess := c.current()
+	if sess == nil {
+		c.noCurrentSession("GetCurrentURL")
+		return ""
 	}
+	return sess.GetCurrentURL()
 }
 
-func (c *chromeWebView) SetReadOnly(elementID string, readOnly bool) {
-	el, err := c.page.Element("#" + elementID)
-	if err != nil {
-		slog.Error("SetReadOnly: element not found", "elementID", elementID, "error", err)
+func (c *chromeWebView) GetValue(id string) string {
+	sess := c.current()
+	if sess == nil {
+		c.noCurrentSession("GetValue")
+		return ""
+	}
+	return sess.GetValue(id)
+}
+
+func (c *chromeWebView) SetValue(id, value string) {
+	sess := c.current()
+	if sess == nil {
+		c.noCurrentSession("SetValue")
 		return
 	}
+	sess.SetValue(id, value)
+}
 
-	_, err = el.Eval(`(readOnly) => {
-		if (this.tagName === 'INPUT' || this.tagName === 'TEXTAREA') {
-			this.readOnly = readOnly;
-			this.disabled = readOnly;
-			if (readOnly) {
-				this.style.backgroundColor = '#f0f0f0';
-				this.style.cursor = 'not-allowed';
-			} else {
-				this.style.backgroundColor = '';
-				this.style.cursor = '';
-			}
-		} else if (this.tagName === 'SELECT') {
-			this.disabled = readOnly;
-		} else {
-			this.contentEditable = readOnly ? 'false' : 'true';
-		}
-	}`, readOnly)
-	if err != nil {
-		slog.Error("SetReadOnly failed", "elementID", elementID, "error", err)
+func (c *chromeWebView) SetReadOnly(id string, readOnly bool) {
+	sess := c.current()
+	if sess == nil {
+		c.noCurrentSession("SetReadOnly")
+		return
 	}
+	sess.SetReadOnly(id, readOnly)
+}
+
+func (c *chromeWebView) AddListener(id string) {
+	sess := c.current()
+	if sess == nil {
+		c.noCurrentSession("AddListener")
+		return
+	}
+	sess.AddListener(id)
+}
+
+func (c *chromeWebView) RemoveListener(id string) {
+	sess := c.current()
+	if sess == nil {
+		c.noCurrentSession("RemoveListener")
+		return
+	}
+	sess.RemoveListener(id)
+}
+
+func (c *chromeWebView) OnChange(id string, cb func(string)) {
+	sess := c.current()
+	if sess == nil {
+		c.noCurrentSession("OnChange")
+		return
+	}
+	sess.OnChange(id, cb)
 }
 
 func (c *chromeWebView) SetCookie(key, value, domain string) {
+	sess := c.current()
+	if sess == nil {
+		c.noCurrentSession("SetCookie")
+		return
+	}
+
 	cookies := []*proto.NetworkCookieParam{
 		{
 			Name:   key,
@@ -184,16 +334,22 @@ func (c *chromeWebView) SetCookie(key, value, domain string) {
 			Path:   "/",
 		},
 	}
-	c.page.SetCookies(cookies)
+	sess.page.SetCookies(cookies)
 }
 
 func (c *chromeWebView) GetCookie(key, domain string) string {
+	sess := c.current()
+	if sess == nil {
+		c.noCurrentSession("GetCookie")
+		return ""
+	}
+
 	// 特定ドメインのCookieを取得するためにURLを指定
 	var urls []string
 	if domain != "" {
 		urls = []string{"https://" + domain}
 	}
-	cookies, err := c.page.Cookies(urls)
+	cookies, err := sess.page.Cookies(urls)
 	if err != nil {
 		slog.Error("GetCookie failed", "error", err)
 		return ""
@@ -212,75 +368,12 @@ func (c *chromeWebView) GetCookie(key, domain string) string {
 }
 
 func (c *chromeWebView) ClearCookie() {
-	c.page.SetCookies(nil)
-}
-
-func (c *chromeWebView) AddListener(elementID string) {
-	c.mu.Lock()
-	c.listeners[elementID] = true
-	c.mu.Unlock()
-
-	c.setupListener(elementID)
-}
-
-func (c *chromeWebView) RemoveListener(elementID string) {
-	c.mu.Lock()
-	delete(c.listeners, elementID)
-	delete(c.elements, elementID)
-	c.mu.Unlock()
-}
-
-func (c *chromeWebView) setupListener(elementID string) {
-	// 初期値を取得
-	el, err := c.page.Element("#" + elementID)
-	if err != nil {
-		slog.Error("setupListener: element not found", "elementID", elementID, "error", err)
+	sess := c.current()
+	if sess == nil {
+		c.noCurrentSession("ClearCookie")
 		return
 	}
-
-	prop, err := el.Property("value")
-	if err == nil {
-		c.mu.Lock()
-		c.elements[elementID] = prop.Str()
-		c.mu.Unlock()
-	}
-
-	// ポーリングで値を監視するgoroutineを起動
-	go c.pollElementValue(elementID)
-}
-
-func (c *chromeWebView) pollElementValue(elementID string) {
-	ticker := time.NewTicker(500 * time.Millisecond)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-c.ctx.Done():
-			return
-		case <-ticker.C:
-			c.mu.RLock()
-			_, exists := c.listeners[elementID]
-			c.mu.RUnlock()
-
-			if !exists {
-				return
-			}
-
-			el, err := c.page.Element("#" + elementID)
-			if err != nil {
-				continue
-			}
-
-			prop, err := el.Property("value")
-			if err != nil {
-				continue
-			}
-
-			c.mu.Lock()
-			c.elements[elementID] = prop.Str()
-			c.mu.Unlock()
-		}
-	}
+	sess.page.SetCookies(nil)
 }
 
 func (c *chromeWebView) Run() {
@@ -294,7 +387,18 @@ func (c *chromeWebView) Run() {
 func (c *chromeWebView) Destroy() {
 	c.cancel()
 	close(c.stopChan)
-	c.page.Close()
+
+	c.mu.RLock()
+	sessions := make([]*session, 0, len(c.sessions))
+	for _, sess := range c.sessions {
+		sessions = append(sessions, sess)
+	}
+	c.mu.RUnlock()
+
+	for _, sess := range sessions {
+		sess.Close()
+	}
+
 	c.browser.Close()
 
 	// 一時ディレクトリが存在する場合は削除
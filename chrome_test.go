@@ -201,6 +201,79 @@ func TestChromeWebView_Listener(t *testing.T) {
 	}
 }
 
+func TestChromeWebView_MultiTab(t *testing.T) {
+	wv := NewChromeWebView()
+	defer wv.Destroy()
+
+	wv.Navigate("https://example.com/")
+
+	first := wv.Tabs()
+	if len(first) != 1 {
+		t.Fatalf("expected 1 tab, got %d", len(first))
+	}
+
+	tab := wv.NewTab("https://example.com/")
+	if tab == nil {
+		t.Fatal("NewTab returned nil")
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	tabs := wv.Tabs()
+	if len(tabs) != 2 {
+		t.Fatalf("expected 2 tabs, got %d", len(tabs))
+	}
+
+	wv.Switch(tab)
+	if wv.GetCurrentURL() != "https://example.com/" {
+		t.Errorf("expected current session to be the new tab, got %s", wv.GetCurrentURL())
+	}
+
+	tab.Close()
+	time.Sleep(500 * time.Millisecond)
+
+	tabs = wv.Tabs()
+	if len(tabs) != 1 {
+		t.Fatalf("expected 1 tab after close, got %d", len(tabs))
+	}
+
+	// current()がnilに落ちても操作がpanicしないことを確認する
+	for _, remaining := range tabs {
+		remaining.Close()
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	url := wv.GetCurrentURL()
+	if url != "" {
+		t.Errorf("expected empty string with no current session, got %s", url)
+	}
+}
+
+func TestChromeWebView_OnChange(t *testing.T) {
+	wv := NewChromeWebView()
+	defer wv.Destroy()
+
+	wv.Navigate("data:text/html,<html><body><input id='onchange-test' type='text'></body></html>")
+
+	time.Sleep(500 * time.Millisecond)
+
+	received := make(chan string, 1)
+	wv.OnChange("onchange-test", func(value string) {
+		received <- value
+	})
+	time.Sleep(500 * time.Millisecond)
+
+	wv.SetValue("onchange-test", "pushed value")
+
+	select {
+	case value := <-received:
+		if value != "pushed value" {
+			t.Errorf("expected 'pushed value', got '%s'", value)
+		}
+	case <-time.After(3 * time.Second):
+		t.Error("timed out waiting for OnChange callback")
+	}
+}
+
 // findDefaultProfile はDefaultディレクトリのプロファイルを返すテストヘルパー
 func findDefaultProfile(profiles []ChromeProfile) *ChromeProfile {
 	for i, p := range profiles {
@@ -0,0 +1,188 @@
+package webview
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// jsonCookie はFormatJSON（chromedpスタイル）のシリアライズ形式
+type jsonCookie struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	Domain   string  `json:"domain"`
+	Path     string  `json:"path"`
+	Expires  float64 `json:"expires"`
+	HTTPOnly bool    `json:"httpOnly"`
+	Secure   bool    `json:"secure"`
+	SameSite string  `json:"sameSite"`
+}
+
+func (c *chromeWebView) ExportCookies(path string, format CookieFormat) error {
+	sess := c.current()
+	if sess == nil {
+		return fmt.Errorf("ExportCookies: no current session (all tabs closed?)")
+	}
+
+	result, err := proto.NetworkGetAllCookies{}.Call(sess.page)
+	if err != nil {
+		return fmt.Errorf("ExportCookies: get cookies: %w", err)
+	}
+
+	if format == FormatJSON {
+		return writeJSONCookies(path, result.Cookies)
+	}
+	return writeNetscapeCookies(path, result.Cookies)
+}
+
+func (c *chromeWebView) ImportCookies(path string, format CookieFormat) error {
+	sess := c.current()
+	if sess == nil {
+		return fmt.Errorf("ImportCookies: no current session (all tabs closed?)")
+	}
+
+	var cookies []*proto.NetworkCookieParam
+	var err error
+
+	if format == FormatJSON {
+		cookies, err = readJSONCookies(path)
+	} else {
+		cookies, err = readNetscapeCookies(path)
+	}
+	if err != nil {
+		return fmt.Errorf("ImportCookies: %w", err)
+	}
+
+	return proto.NetworkSetCookies{Cookies: cookies}.Call(sess.page)
+}
+
+func writeJSONCookies(path string, cookies []*proto.NetworkCookie) error {
+	out := make([]jsonCookie, 0, len(cookies))
+	for _, ck := range cookies {
+		out = append(out, jsonCookie{
+			Name:     ck.Name,
+			Value:    ck.Value,
+			Domain:   ck.Domain,
+			Path:     ck.Path,
+			Expires:  float64(ck.Expires),
+			HTTPOnly: ck.HTTPOnly,
+			Secure:   ck.Secure,
+			SameSite: string(ck.SameSite),
+		})
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func readJSONCookies(path string) ([]*proto.NetworkCookieParam, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var in []jsonCookie
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, err
+	}
+
+	params := make([]*proto.NetworkCookieParam, 0, len(in))
+	for _, ck := range in {
+		params = append(params, &proto.NetworkCookieParam{
+			Name:     ck.Name,
+			Value:    ck.Value,
+			Domain:   ck.Domain,
+			Path:     ck.Path,
+			Expires:  proto.TimeSinceEpoch(ck.Expires),
+			HTTPOnly: ck.HTTPOnly,
+			Secure:   ck.Secure,
+			SameSite: proto.NetworkCookieSameSite(ck.SameSite),
+		})
+	}
+	return params, nil
+}
+
+// httpOnlyPrefix はcurl/wget/Chrome自身がNetscape形式でHttpOnly Cookieを表す際に
+// domainフィールドの先頭に付与する慣習的なプレフィックス
+const httpOnlyPrefix = "#HttpOnly_"
+
+// writeNetscapeCookies は"# Netscape HTTP Cookie File"形式で書き出す
+// 各行はタブ区切りで domain, includeSubdomains, path, secure, expires, name, value の順
+// HttpOnlyなCookieはdomainの前に#HttpOnly_を付与して区別する（curl/wget/Chrome自身の慣習）
+func writeNetscapeCookies(path string, cookies []*proto.NetworkCookie) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	fmt.Fprintln(w, "# Netscape HTTP Cookie File")
+	for _, ck := range cookies {
+		domain := ck.Domain
+		if ck.HTTPOnly {
+			domain = httpOnlyPrefix + domain
+		}
+		includeSubdomains := "FALSE"
+		if strings.HasPrefix(ck.Domain, ".") {
+			includeSubdomains = "TRUE"
+		}
+		secure := "FALSE"
+		if ck.Secure {
+			secure = "TRUE"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			domain, includeSubdomains, ck.Path, secure, int64(ck.Expires), ck.Name, ck.Value)
+	}
+	return w.Flush()
+}
+
+func readNetscapeCookies(path string) ([]*proto.NetworkCookieParam, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var params []*proto.NetworkCookieParam
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		httpOnly := false
+		if strings.HasPrefix(line, httpOnlyPrefix) {
+			httpOnly = true
+			line = strings.TrimPrefix(line, httpOnlyPrefix)
+		} else if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			continue
+		}
+
+		expires, _ := strconv.ParseInt(fields[4], 10, 64)
+		params = append(params, &proto.NetworkCookieParam{
+			Domain:   fields[0],
+			Path:     fields[2],
+			Secure:   fields[3] == "TRUE",
+			Expires:  proto.TimeSinceEpoch(expires),
+			Name:     fields[5],
+			Value:    fields[6],
+			HTTPOnly: httpOnly,
+		})
+	}
+	return params, scanner.Err()
+}
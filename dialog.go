@@ -0,0 +1,104 @@
+package webview
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// SetDialogHandler はネイティブダイアログが開いた際の応答を決めるハンドラを登録する
+// 既に開いている全タブ、および今後開かれるタブすべてに適用される
+func (c *chromeWebView) SetDialogHandler(handler func(DialogEvent) DialogResponse) {
+	c.mu.Lock()
+	c.dialogHandler = handler
+	c.mu.Unlock()
+}
+
+// AutoAcceptDialogs はハンドラ未設定時にネイティブダイアログを自動的に受諾するかどうかを設定する
+func (c *chromeWebView) AutoAcceptDialogs(accept bool) {
+	c.mu.Lock()
+	c.autoAcceptDialogs = accept
+	c.mu.Unlock()
+}
+
+// SetDownloadDir はダウンロード先ディレクトリを設定する
+func (c *chromeWebView) SetDownloadDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("SetDownloadDir: %w", err)
+	}
+
+	err := proto.BrowserSetDownloadBehavior{
+		Behavior:      proto.BrowserSetDownloadBehaviorBehaviorAllowAndName,
+		DownloadPath:  dir,
+		EventsEnabled: true,
+	}.Call(c.browser)
+	if err != nil {
+		return fmt.Errorf("SetDownloadDir: %w", err)
+	}
+
+	c.mu.Lock()
+	c.downloadDir = dir
+	c.mu.Unlock()
+
+	c.ensureDownloadBinding()
+	return nil
+}
+
+// WaitDownload は次のダウンロードが完了するまで待機し、保存先パスを返す
+func (c *chromeWebView) WaitDownload(timeout time.Duration) (string, error) {
+	select {
+	case event := <-c.downloads:
+		return event.Path, nil
+	case <-time.After(timeout):
+		return "", fmt.Errorf("WaitDownload: timed out after %s", timeout)
+	}
+}
+
+// ensureDownloadBinding はBrowserDownloadWillBegin/Progressの購読を一度だけ開始する
+// ダウンロードはブラウザ全体で共有されるためchromeWebViewが一括で管理する
+func (c *chromeWebView) ensureDownloadBinding() {
+	c.downloadOnce.Do(func() {
+		wait := c.browser.EachEvent(
+			func(e *proto.BrowserDownloadWillBegin) {
+				c.mu.Lock()
+				c.pendingDownloads[e.GUID] = pendingDownload{url: e.URL, filename: e.SuggestedFilename}
+				c.mu.Unlock()
+			},
+			func(e *proto.BrowserDownloadProgress) {
+				if e.State != proto.BrowserDownloadProgressStateCompleted {
+					return
+				}
+
+				c.mu.Lock()
+				pending, ok := c.pendingDownloads[e.GUID]
+				delete(c.pendingDownloads, e.GUID)
+				dir := c.downloadDir
+				c.mu.Unlock()
+
+				if !ok {
+					return
+				}
+
+				// allowAndName はファイルをsuggestedFilenameではなくGUIDで保存するため、
+				// 実際に書き込まれるパスはdir/<GUID>になる
+				event := DownloadEvent{
+					GUID:     e.GUID,
+					URL:      pending.url,
+					FileName: pending.filename,
+					Path:     filepath.Join(dir, e.GUID),
+				}
+
+				select {
+				case c.downloads <- event:
+				default:
+					slog.Warn("ensureDownloadBinding: download event channel full, dropping", "guid", e.GUID)
+				}
+			},
+		)
+		go wait()
+	})
+}
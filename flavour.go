@@ -0,0 +1,241 @@
+package webview
+
+import (
+	"bufio"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// BrowserFlavour はプロファイルを持つブラウザの種類を表す
+// Name・StateFile・Firefoxだけで構成され、全てのフィールドが比較可能なので
+// map[BrowserFlavour]...のキーとして使える
+type BrowserFlavour struct {
+	// Name はブラウザの表示名（OSPathsの登録キーにもなる）
+	Name string
+	// StateFile はプロファイル一覧が記載された状態ファイル名
+	// Chromium系は"Local State"、Firefox系は"profiles.ini"
+	StateFile string
+	// Firefox はtrueの場合、StateFileをprofiles.ini形式でパースする
+	Firefox bool
+}
+
+// UserDataDir はこのフレーバーのユーザーデータディレクトリを返す
+func (f BrowserFlavour) UserDataDir() string {
+	paths, ok := flavourPaths[f.Name]
+	if !ok {
+		slog.Error("UserDataDir: flavour not registered", "flavour", f.Name)
+		return ""
+	}
+	return paths.resolve()
+}
+
+// OSPaths はOSごとのユーザーデータディレクトリをホームディレクトリ（または
+// Windowsでは%LOCALAPPDATA%）からの相対パス要素で表す
+type OSPaths struct {
+	Darwin  []string
+	Windows []string
+	Linux   []string
+}
+
+func (p OSPaths) resolve() string {
+	switch runtime.GOOS {
+	case "darwin":
+		home, _ := os.UserHomeDir()
+		return filepath.Join(append([]string{home}, p.Darwin...)...)
+	case "windows":
+		return filepath.Join(append([]string{os.Getenv("LOCALAPPDATA")}, p.Windows...)...)
+	default: // linux
+		home, _ := os.UserHomeDir()
+		return filepath.Join(append([]string{home}, p.Linux...)...)
+	}
+}
+
+// flavourPaths はBrowserFlavour.Nameごとのパス定義レジストリ
+var flavourPaths = map[string]OSPaths{}
+
+// RegisterFlavour は新しいブラウザフレーバーをレジストリに登録する
+// コア側のコードを変更することなく新しいブラウザを追加できる
+func RegisterFlavour(flavour BrowserFlavour, paths OSPaths) {
+	flavourPaths[flavour.Name] = paths
+	for _, f := range AllFlavours {
+		if f == flavour {
+			return
+		}
+	}
+	AllFlavours = append(AllFlavours, flavour)
+}
+
+// 標準でサポートするブラウザフレーバー
+var (
+	FlavourChrome         = BrowserFlavour{Name: "Google Chrome", StateFile: "Local State"}
+	FlavourChromeCanary   = BrowserFlavour{Name: "Google Chrome Canary", StateFile: "Local State"}
+	FlavourChromeBeta     = BrowserFlavour{Name: "Google Chrome Beta", StateFile: "Local State"}
+	FlavourChromeUnstable = BrowserFlavour{Name: "Google Chrome Unstable", StateFile: "Local State"}
+	FlavourChromium       = BrowserFlavour{Name: "Chromium", StateFile: "Local State"}
+	FlavourBrave          = BrowserFlavour{Name: "Brave", StateFile: "Local State"}
+	FlavourEdge           = BrowserFlavour{Name: "Microsoft Edge", StateFile: "Local State"}
+	FlavourVivaldi        = BrowserFlavour{Name: "Vivaldi", StateFile: "Local State"}
+	FlavourOpera          = BrowserFlavour{Name: "Opera", StateFile: "Local State"}
+	FlavourFirefox        = BrowserFlavour{Name: "Mozilla Firefox", StateFile: "profiles.ini", Firefox: true}
+	FlavourLibreWolf      = BrowserFlavour{Name: "LibreWolf", StateFile: "profiles.ini", Firefox: true}
+)
+
+// AllFlavours はRegisterFlavourで登録済みの全フレーバーの一覧
+var AllFlavours = []BrowserFlavour{
+	FlavourChrome,
+	FlavourChromeCanary,
+	FlavourChromeBeta,
+	FlavourChromeUnstable,
+	FlavourChromium,
+	FlavourBrave,
+	FlavourEdge,
+	FlavourVivaldi,
+	FlavourOpera,
+	FlavourFirefox,
+	FlavourLibreWolf,
+}
+
+func init() {
+	RegisterFlavour(FlavourChrome, OSPaths{
+		Darwin:  []string{"Library", "Application Support", "Google", "Chrome"},
+		Windows: []string{"Google", "Chrome", "User Data"},
+		Linux:   []string{".config", "google-chrome"},
+	})
+	RegisterFlavour(FlavourChromeCanary, OSPaths{
+		Darwin:  []string{"Library", "Application Support", "Google", "Chrome Canary"},
+		Windows: []string{"Google", "Chrome SxS", "User Data"},
+		Linux:   []string{".config", "google-chrome-canary"},
+	})
+	RegisterFlavour(FlavourChromeBeta, OSPaths{
+		Darwin:  []string{"Library", "Application Support", "Google", "Chrome Beta"},
+		Windows: []string{"Google", "Chrome Beta", "User Data"},
+		Linux:   []string{".config", "google-chrome-beta"},
+	})
+	RegisterFlavour(FlavourChromeUnstable, OSPaths{
+		Darwin:  []string{"Library", "Application Support", "Google", "Chrome Dev"},
+		Windows: []string{"Google", "Chrome Dev", "User Data"},
+		Linux:   []string{".config", "google-chrome-unstable"},
+	})
+	RegisterFlavour(FlavourChromium, OSPaths{
+		Darwin:  []string{"Library", "Application Support", "Chromium"},
+		Windows: []string{"Chromium", "User Data"},
+		Linux:   []string{".config", "chromium"},
+	})
+	RegisterFlavour(FlavourBrave, OSPaths{
+		Darwin:  []string{"Library", "Application Support", "BraveSoftware", "Brave-Browser"},
+		Windows: []string{"BraveSoftware", "Brave-Browser", "User Data"},
+		Linux:   []string{".config", "BraveSoftware", "Brave-Browser"},
+	})
+	RegisterFlavour(FlavourEdge, OSPaths{
+		Darwin:  []string{"Library", "Application Support", "Microsoft Edge"},
+		Windows: []string{"Microsoft", "Edge", "User Data"},
+		Linux:   []string{".config", "microsoft-edge"},
+	})
+	RegisterFlavour(FlavourVivaldi, OSPaths{
+		Darwin:  []string{"Library", "Application Support", "Vivaldi"},
+		Windows: []string{"Vivaldi", "User Data"},
+		Linux:   []string{".config", "vivaldi"},
+	})
+	RegisterFlavour(FlavourOpera, OSPaths{
+		Darwin:  []string{"Library", "Application Support", "com.operasoftware.Opera"},
+		Windows: []string{"Opera Software", "Opera Stable"},
+		Linux:   []string{".config", "opera"},
+	})
+	RegisterFlavour(FlavourFirefox, OSPaths{
+		Darwin:  []string{"Library", "Application Support", "Firefox"},
+		Windows: []string{"Mozilla", "Firefox"},
+		Linux:   []string{".mozilla", "firefox"},
+	})
+	RegisterFlavour(FlavourLibreWolf, OSPaths{
+		Darwin:  []string{"Library", "Application Support", "librewolf"},
+		Windows: []string{"librewolf"},
+		Linux:   []string{".librewolf"},
+	})
+}
+
+// ListProfiles は指定したフレーバーのプロファイル一覧を取得する
+func ListProfiles(flavour BrowserFlavour) ([]ChromeProfile, error) {
+	statePath := filepath.Join(flavour.UserDataDir(), flavour.StateFile)
+	if flavour.Firefox {
+		return parseFirefoxProfilesIni(statePath)
+	}
+	return parseChromiumLocalState(statePath, flavour.UserDataDir())
+}
+
+// ListAllProfiles はAllFlavoursに登録された全ブラウザのプロファイルを取得する
+// インストールされていないブラウザはスキップされる
+func ListAllProfiles() map[BrowserFlavour][]ChromeProfile {
+	result := make(map[BrowserFlavour][]ChromeProfile)
+	for _, flavour := range AllFlavours {
+		profiles, err := ListProfiles(flavour)
+		if err != nil {
+			slog.Debug("ListAllProfiles: skipping flavour", "flavour", flavour.Name, "error", err)
+			continue
+		}
+		result[flavour] = profiles
+	}
+	return result
+}
+
+// parseFirefoxProfilesIni はFirefox/LibreWolf形式のprofiles.iniをパースする
+func parseFirefoxProfilesIni(path string) ([]ChromeProfile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	baseDir := filepath.Dir(path)
+	var profiles []ChromeProfile
+	section := ""
+	fields := map[string]string{}
+
+	flush := func() {
+		if !strings.HasPrefix(section, "Profile") {
+			return
+		}
+		relPath := fields["Path"]
+		if relPath == "" {
+			return
+		}
+		path := relPath
+		isRelative, _ := strconv.ParseBool(fields["IsRelative"])
+		if fields["IsRelative"] == "" || isRelative {
+			path = filepath.Join(baseDir, relPath)
+		}
+		profiles = append(profiles, ChromeProfile{
+			Name:      fields["Name"],
+			Directory: relPath,
+			Path:      path,
+		})
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			flush()
+			section = strings.Trim(line, "[]")
+			fields = map[string]string{}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		fields[key] = value
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
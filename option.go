@@ -1,4 +1,4 @@
-package rod_wrap
+package webview
 
 import (
 	"log/slog"
@@ -46,6 +46,26 @@ func WithChromeProfile(profile ChromeProfile) ChromeOption {
 	}
 }
 
+// WithFlavourProfile は指定したブラウザフレーバーとプロファイルを使用する
+// Chrome以外のChromium系ブラウザ（Brave、Edge、Vivaldiなど）のプロファイルを
+// 直接指定する場合に使う
+// 注意: 起動されるのは常にlauncher.LookPathが見つけたシステムのChromium系バイナリであり、
+// これはCDPを話さないFirefox/LibreWolfプロファイルには対応していない
+// （flavour.Firefoxがtrueの場合は何もせずエラーをログ出力する）。
+// Firefoxプロファイルの中身を読み取るだけならListProfiles(FlavourFirefox)や
+// browsingdataパッケージを使うこと
+// 注意: 既存の同ブラウザが起動中の場合、同じプロファイルは使用できない
+func WithFlavourProfile(flavour BrowserFlavour, profile ChromeProfile) ChromeOption {
+	return func(o *chromeOptions) {
+		if flavour.Firefox {
+			slog.Error("WithFlavourProfile: launching Firefox-family profiles is not supported; NewChromeWebView only speaks CDP to a Chromium-family binary", "flavour", flavour.Name)
+			return
+		}
+		o.userDataDir = flavour.UserDataDir()
+		o.profileDir = profile.Directory
+	}
+}
+
 // WithCopiedProfile は既存のプロファイルを一時ディレクトリにコピーして使用する
 // セッション、Cookie、ログイン情報などが引き継がれる
 // 既存のChromeが起動中でも使用可能
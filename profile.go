@@ -33,9 +33,11 @@ func GetChromeUserDataDir() string {
 
 // ListChromeProfiles はChromeのプロファイル一覧を取得する
 func ListChromeProfiles() ([]ChromeProfile, error) {
-	userDataDir := GetChromeUserDataDir()
-	localStatePath := filepath.Join(userDataDir, "Local State")
+	return ListProfiles(FlavourChrome)
+}
 
+// parseChromiumLocalState はChromium系ブラウザの"Local State"からプロファイル一覧を取得する
+func parseChromiumLocalState(localStatePath, userDataDir string) ([]ChromeProfile, error) {
 	data, err := os.ReadFile(localStatePath)
 	if err != nil {
 		return nil, err
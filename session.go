@@ -0,0 +1,354 @@
+package webview
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// session は1つのタブ（rod.Page）に紐づく状態をまとめたもの
+// chromeWebViewは複数のsessionを保持し、そのうち1つを「current」として
+// WebViewインターフェースの操作対象にする
+type session struct {
+	owner *chromeWebView
+	id    proto.TargetTargetID
+	page  *rod.Page
+
+	elements        map[string]string
+	listeners       map[string]bool
+	changeCallbacks map[string][]func(string)
+	bindingOnce     sync.Once
+	dialogOnce      sync.Once
+	mu              sync.RWMutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newSession(owner *chromeWebView, page *rod.Page) *session {
+	var id proto.TargetTargetID
+	if info, err := page.Info(); err == nil {
+		id = info.TargetID
+	} else {
+		slog.Warn("newSession: failed to read target info", "error", err)
+	}
+
+	ctx, cancel := context.WithCancel(owner.ctx)
+	return &session{
+		owner:           owner,
+		id:              id,
+		page:            page,
+		elements:        make(map[string]string),
+		listeners:       make(map[string]bool),
+		changeCallbacks: make(map[string][]func(string)),
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+}
+
+func (s *session) ID() string {
+	return string(s.id)
+}
+
+func (s *session) Navigate(url string) {
+	slog.Info("Navigate", "url", url, "session", s.id)
+
+	// Navigate前に既存の変数をクリア
+	s.mu.Lock()
+	s.elements = make(map[string]string)
+	s.mu.Unlock()
+
+	if err := s.page.Navigate(url); err != nil {
+		slog.Error("Navigate failed", "error", err)
+		return
+	}
+
+	s.page.MustWaitLoad()
+
+	// リスナーが設定されている場合は再設定
+	// setupListenerは内部でs.mu.Lock()を取得するため、RLockを保持したまま
+	// 呼び出すとデッドロックする。対象のelementIDを先に読み取ってからロックを外す
+	s.mu.RLock()
+	elementIDs := make([]string, 0, len(s.listeners))
+	for elementID := range s.listeners {
+		elementIDs = append(elementIDs, elementID)
+	}
+	s.mu.RUnlock()
+
+	for _, elementID := range elementIDs {
+		s.setupListener(elementID)
+	}
+}
+
+func (s *session) GetCurrentURL() string {
+	info, err := s.page.Info()
+	if err != nil {
+		slog.Error("GetCurrentURL failed", "error", err)
+		return ""
+	}
+	return info.URL
+}
+
+func (s *session) GetValue(elementID string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.elements[elementID]
+}
+
+func (s *session) SetValue(elementID, value string) {
+	s.mu.Lock()
+	s.elements[elementID] = value
+	s.mu.Unlock()
+
+	el, err := s.page.Element("#" + elementID)
+	if err != nil {
+		slog.Error("SetValue: element not found", "elementID", elementID, "error", err)
+		return
+	}
+
+	if err := el.Input(value); err != nil {
+		slog.Error("SetValue: input failed", "elementID", elementID, "error", err)
+	}
+}
+
+func (s *session) SetReadOnly(elementID string, readOnly bool) {
+	el, err := s.page.Element("#" + elementID)
+	if err != nil {
+		slog.Error("SetReadOnly: element not found", "elementID", elementID, "error", err)
+		return
+	}
+
+	_, err = el.Eval(`(readOnly) => {
+		if (this.tagName === 'INPUT' || this.tagName === 'TEXTAREA') {
+			this.readOnly = readOnly;
+			this.disabled = readOnly;
+			if (readOnly) {
+				this.style.backgroundColor = '#f0f0f0';
+				this.style.cursor = 'not-allowed';
+			} else {
+				this.style.backgroundColor = '';
+				this.style.cursor = '';
+			}
+		} else if (this.tagName === 'SELECT') {
+			this.disabled = readOnly;
+		} else {
+			this.contentEditable = readOnly ? 'false' : 'true';
+		}
+	}`, readOnly)
+	if err != nil {
+		slog.Error("SetReadOnly failed", "elementID", elementID, "error", err)
+	}
+}
+
+func (s *session) AddListener(elementID string) {
+	s.mu.Lock()
+	s.listeners[elementID] = true
+	s.mu.Unlock()
+
+	s.setupListener(elementID)
+}
+
+func (s *session) RemoveListener(elementID string) {
+	s.mu.Lock()
+	delete(s.listeners, elementID)
+	delete(s.elements, elementID)
+	delete(s.changeCallbacks, elementID)
+	s.mu.Unlock()
+
+	el, err := s.page.Element("#" + elementID)
+	if err != nil {
+		// ページ遷移済みなどで要素が無い場合はJS側の後始末は諦める
+		return
+	}
+
+	_, err = el.Eval(`(id) => {
+		const entry = window.__rodWrapObservers && window.__rodWrapObservers[id];
+		if (!entry) return;
+		entry.observer.disconnect();
+		entry.el.removeEventListener('input', entry.notify);
+		entry.el.removeEventListener('change', entry.notify);
+		delete window.__rodWrapObservers[id];
+	}`, elementID)
+	if err != nil {
+		slog.Warn("RemoveListener: failed to tear down observer", "elementID", elementID, "error", err)
+	}
+}
+
+// OnChange は指定した要素の値が変化するたびにコールバックを呼び出す
+func (s *session) OnChange(elementID string, cb func(string)) {
+	s.mu.Lock()
+	s.changeCallbacks[elementID] = append(s.changeCallbacks[elementID], cb)
+	_, alreadyListening := s.listeners[elementID]
+	s.listeners[elementID] = true
+	s.mu.Unlock()
+
+	if !alreadyListening {
+		s.setupListener(elementID)
+	}
+}
+
+func (s *session) setupListener(elementID string) {
+	// 初期値を取得
+	el, err := s.page.Element("#" + elementID)
+	if err != nil {
+		slog.Error("setupListener: element not found", "elementID", elementID, "error", err)
+		return
+	}
+
+	prop, err := el.Property("value")
+	if err == nil {
+		s.mu.Lock()
+		s.elements[elementID] = prop.Str()
+		s.mu.Unlock()
+	}
+
+	s.ensureChangeBinding()
+
+	// MutationObserverとinput/changeイベントでDOM変更をGo側にプッシュ通知する
+	// el.Evalはスクリプトを要素をthisとして呼び出すため、関数内のthisは監視対象の要素になる
+	// （SetReadOnlyのアロー関数と同様）
+	_, err = el.Eval(`function(id) {
+		window.__rodWrapObservers = window.__rodWrapObservers || {};
+		if (window.__rodWrapObservers[id]) {
+			return;
+		}
+
+		const el = this;
+		const notify = function() { window.`+rodWrapBindingName+`(JSON.stringify({id: id, value: el.value})); };
+
+		const observer = new MutationObserver(notify);
+		observer.observe(el, {attributes: true, attributeFilter: ['value']});
+		el.addEventListener('input', notify);
+		el.addEventListener('change', notify);
+
+		window.__rodWrapObservers[id] = {observer: observer, notify: notify, el: el};
+	}`, elementID)
+	if err != nil {
+		slog.Warn("setupListener: MutationObserver setup failed, falling back to polling", "elementID", elementID, "error", err)
+		go s.pollElementValue(elementID)
+	}
+}
+
+// ensureChangeBinding はRuntimeBindingCalledを受け取るグローバル関数を一度だけ登録する
+func (s *session) ensureChangeBinding() {
+	s.bindingOnce.Do(func() {
+		binding := proto.RuntimeAddBinding{Name: rodWrapBindingName}
+		if err := binding.Call(s.page); err != nil {
+			slog.Error("ensureChangeBinding: RuntimeAddBinding failed", "error", err)
+			return
+		}
+
+		wait := s.page.EachEvent(func(e *proto.RuntimeBindingCalled) {
+			if e.Name != rodWrapBindingName {
+				return
+			}
+
+			var payload struct {
+				ID    string `json:"id"`
+				Value string `json:"value"`
+			}
+			if err := json.Unmarshal([]byte(e.Payload), &payload); err != nil {
+				slog.Warn("ensureChangeBinding: invalid payload", "payload", e.Payload, "error", err)
+				return
+			}
+
+			s.handleValueChanged(payload.ID, payload.Value)
+		})
+		go wait()
+	})
+}
+
+// handleValueChanged はブラウザ側から通知された値の変化をelementsに反映し、
+// OnChangeで登録されたコールバックを呼び出す
+func (s *session) handleValueChanged(elementID, value string) {
+	s.mu.Lock()
+	_, listening := s.listeners[elementID]
+	if listening {
+		s.elements[elementID] = value
+	}
+	callbacks := append([]func(string){}, s.changeCallbacks[elementID]...)
+	s.mu.Unlock()
+
+	if !listening {
+		return
+	}
+
+	for _, cb := range callbacks {
+		cb(value)
+	}
+}
+
+func (s *session) pollElementValue(elementID string) {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.mu.RLock()
+			_, exists := s.listeners[elementID]
+			s.mu.RUnlock()
+
+			if !exists {
+				return
+			}
+
+			el, err := s.page.Element("#" + elementID)
+			if err != nil {
+				continue
+			}
+
+			prop, err := el.Property("value")
+			if err != nil {
+				continue
+			}
+
+			s.mu.Lock()
+			s.elements[elementID] = prop.Str()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// ensureDialogBinding はPageJavascriptDialogOpeningの購読を一度だけ開始する
+// ハンドラ本体はowner（chromeWebView）が保持する設定を都度参照する
+func (s *session) ensureDialogBinding() {
+	s.dialogOnce.Do(func() {
+		wait := s.page.EachEvent(func(e *proto.PageJavascriptDialogOpening) {
+			handler, autoAccept := s.owner.dialogSettings()
+
+			resp := DialogResponse{Accept: autoAccept}
+			if handler != nil {
+				resp = handler(DialogEvent{
+					Type:    string(e.Type),
+					Message: e.Message,
+					URL:     e.URL,
+				})
+			}
+
+			err := proto.PageHandleJavaScriptDialog{
+				Accept:     resp.Accept,
+				PromptText: resp.PromptText,
+			}.Call(s.page)
+			if err != nil {
+				slog.Error("ensureDialogBinding: HandleJavaScriptDialog failed", "error", err)
+			}
+		})
+		go wait()
+	})
+}
+
+// Close はこのタブを閉じる
+func (s *session) Close() {
+	s.cancel()
+	if err := s.page.Close(); err != nil {
+		slog.Warn("Session.Close: failed to close page", "session", s.id, "error", err)
+	}
+}
@@ -1,5 +1,63 @@
 package webview
 
+import "time"
+
+// CookieFormat はExportCookies/ImportCookiesが読み書きするファイル形式
+type CookieFormat int
+
+const (
+	// FormatNetscape はcurl/wgetで使われる"# Netscape HTTP Cookie File"形式
+	FormatNetscape CookieFormat = iota
+	// FormatJSON はchromedpスタイルの{name, value, domain, path, expires, httpOnly, secure, sameSite}配列
+	FormatJSON
+)
+
+// DialogEvent はブラウザのネイティブダイアログ（alert/confirm/prompt/beforeunload）を表す
+type DialogEvent struct {
+	Type    string // "alert", "confirm", "prompt", "beforeunload"
+	Message string
+	URL     string
+}
+
+// DialogResponse はDialogEventへの応答
+type DialogResponse struct {
+	Accept     bool
+	PromptText string // promptダイアログで入力欄に渡す値
+}
+
+// DownloadEvent はダウンロードの完了を表す
+type DownloadEvent struct {
+	GUID     string
+	URL      string
+	FileName string
+	Path     string
+}
+
+// Session は1つのブラウザタブ（ページ）に対する操作対象を表す
+// 要素の値・リスナーなどの状態はSessionごとに独立して保持される
+type Session interface {
+	// ID はこのSessionを一意に識別するID（ブラウザのターゲットID）
+	ID() string
+	// Navigate は指定したURLに移動する
+	Navigate(url string)
+	// GetCurrentURL は現在のURLを取得する
+	GetCurrentURL() string
+	// GetValue は指定した要素の値を取得する
+	GetValue(elementID string) string
+	// SetValue は指定した要素に値を設定する
+	SetValue(elementID, value string)
+	// SetReadOnly は指定した要素の読み取り専用状態を設定する
+	SetReadOnly(elementID string, readOnly bool)
+	// AddListener は指定した要素の値変更を監視する
+	AddListener(elementID string)
+	// RemoveListener は指定した要素の監視を解除する
+	RemoveListener(elementID string)
+	// OnChange は指定した要素の値が変化するたびにコールバックを呼び出す
+	OnChange(elementID string, cb func(string))
+	// Close はこのタブを閉じる
+	Close()
+}
+
 // WebView はブラウザ操作のインターフェース
 type WebView interface {
 	// Navigate は指定したURLに移動する
@@ -18,12 +76,39 @@ type WebView interface {
 	GetCookie(key, domain string) string
 	// ClearCookie は全てのCookieをクリアする
 	ClearCookie()
+	// ExportCookies は現在のセッションの全Cookieを指定した形式でファイルに書き出す
+	// SetCookieと異なりHttpOnly/Secure/Expiryも保持される
+	// SameSiteはFormatJSONでのみ保持され、FormatNetscapeには書き出されない
+	ExportCookies(path string, format CookieFormat) error
+	// ImportCookies は指定した形式のファイルからCookieを読み込みセッションに設定する
+	ImportCookies(path string, format CookieFormat) error
+	// SetDialogHandler はネイティブダイアログが開いた際の応答を決めるハンドラを登録する
+	SetDialogHandler(handler func(DialogEvent) DialogResponse)
+	// AutoAcceptDialogs はハンドラ未設定時にネイティブダイアログを自動的に受諾するかどうかを設定する
+	AutoAcceptDialogs(accept bool)
+	// SetDownloadDir はダウンロード先ディレクトリを設定する
+	SetDownloadDir(dir string) error
+	// WaitDownload は次のダウンロードが完了するまで待機し、保存先パスを返す
+	WaitDownload(timeout time.Duration) (string, error)
 	// AddListener は指定した要素の値変更を監視する
 	AddListener(elementID string)
 	// RemoveListener は指定した要素の監視を解除する
 	RemoveListener(elementID string)
+	// OnChange は指定した要素の値が変化するたびにコールバックを呼び出す
+	// GetValueによるポーリングが不要なプッシュ型の通知API
+	OnChange(elementID string, cb func(string))
 	// Run はブラウザが閉じられるまで待機する
 	Run()
 	// Destroy はブラウザを終了する
 	Destroy()
+
+	// NewTab は新しいタブを開き、そのSessionを返す
+	NewTab(url string) Session
+	// Tabs は現在開いている全タブのSessionを返す
+	Tabs() []Session
+	// Switch は以降の操作対象となるタブを指定したSessionに切り替える
+	Switch(s Session)
+	// OnNewTab はwindow.open、target=_blank、Ctrl+Clickなどで新しいタブが
+	// 開かれた際に呼び出されるコールバックを登録する
+	OnNewTab(cb func(Session))
 }